@@ -2,6 +2,11 @@ package artifactory
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
 
 	cleanhttp "github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/terraform/internal/backend"
@@ -11,21 +16,35 @@ import (
 	artifactory "github.com/lusis/go-artifactory/src/artifactory.v401"
 )
 
+const (
+	workspaceKeyPrefixKey     = "workspace_key_prefix"
+	defaultWorkspaceKeyPrefix = "env:"
+
+	accessTokenKey        = "access_token"
+	accessTokenEnvVarName = "ARTIFACTORY_ACCESS_TOKEN"
+)
+
 func New() backend.Backend {
 	s := &schema.Backend{
 		Schema: map[string]*schema.Schema{
 			"username": {
 				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("ARTIFACTORY_USERNAME", nil),
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ARTIFACTORY_USERNAME", ""),
 				Description: "Username",
 			},
 			"password": {
 				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("ARTIFACTORY_PASSWORD", nil),
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ARTIFACTORY_PASSWORD", ""),
 				Description: "Password",
 			},
+			accessTokenKey: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(accessTokenEnvVarName, ""),
+				Description: "Access token used in place of a username/password for bearer-token authentication",
+			},
 			"url": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -42,6 +61,12 @@ func New() backend.Backend {
 				Required:    true,
 				Description: "Path within the repository",
 			},
+			workspaceKeyPrefixKey: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The prefix applied to the state path inside the repository for all workspaces except default",
+				Default:     defaultWorkspaceKeyPrefix,
+			},
 		},
 	}
 
@@ -53,50 +78,115 @@ func New() backend.Backend {
 type Backend struct {
 	*schema.Backend
 
-	client *ArtifactoryClient
+	nativeClient *artifactory.Artifactory
+	httpClient   *http.Client
+
+	userName           string
+	password           string
+	accessToken        string
+	url                string
+	repo               string
+	subpath            string
+	workspaceKeyPrefix string
 }
 
 func (b *Backend) configure(ctx context.Context) error {
 	data := schema.FromContextBackendConfig(ctx)
 
-	userName := data.Get("username").(string)
-	password := data.Get("password").(string)
-	url := data.Get("url").(string)
-	repo := data.Get("repo").(string)
-	subpath := data.Get("subpath").(string)
+	b.userName = data.Get("username").(string)
+	b.password = data.Get("password").(string)
+	b.accessToken = data.Get(accessTokenKey).(string)
+	b.url = data.Get("url").(string)
+	b.repo = data.Get("repo").(string)
+	b.subpath = data.Get("subpath").(string)
+	b.workspaceKeyPrefix = data.Get(workspaceKeyPrefixKey).(string)
+
+	if b.accessToken == "" && (b.userName == "" || b.password == "") {
+		return fmt.Errorf("either %q or both %q and %q must be set", accessTokenKey, "username", "password")
+	}
 
+	transport := cleanhttp.DefaultPooledTransport()
 	clientConf := &artifactory.ClientConfig{
-		BaseURL:   url,
-		Username:  userName,
-		Password:  password,
-		Transport: cleanhttp.DefaultPooledTransport(),
+		BaseURL:   b.url,
+		Transport: transport,
+	}
+	if b.accessToken != "" {
+		clientConf.Transport = &bearerTokenTransport{token: b.accessToken, base: transport}
+	} else {
+		clientConf.Username = b.userName
+		clientConf.Password = b.password
 	}
 	nativeClient := artifactory.NewClient(clientConf)
+	b.nativeClient = &nativeClient
+	b.httpClient = &http.Client{Transport: clientConf.Transport}
 
-	b.client = &ArtifactoryClient{
-		nativeClient: &nativeClient,
-		userName:     userName,
-		password:     password,
-		url:          url,
-		repo:         repo,
-		subpath:      subpath,
-	}
 	return nil
 }
 
+// client returns an ArtifactoryClient for the given workspace.
+//
+// The default workspace is stored at exactly b.subpath, unchanged from
+// before workspaces were supported, so that existing single-workspace
+// configurations keep working without a migration. Every other workspace
+// is namespaced beneath workspace_key_prefix, mirroring the layout used by
+// the S3 backend.
+func (b *Backend) client(name string) *ArtifactoryClient {
+	return &ArtifactoryClient{
+		nativeClient: b.nativeClient,
+		httpClient:   b.httpClient,
+		userName:     b.userName,
+		password:     b.password,
+		accessToken:  b.accessToken,
+		url:          b.url,
+		repo:         b.repo,
+		subpath:      b.workspaceSubpath(name),
+	}
+}
+
+// bearerTokenTransport adds an Authorization: Bearer header to every
+// request, used in place of HTTP basic auth when access_token is set.
+type bearerTokenTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t *bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
+
+func (b *Backend) workspaceSubpath(name string) string {
+	if name == backend.DefaultStateName {
+		return b.subpath
+	}
+	return path.Join(b.workspaceKeyPrefix, name, b.subpath)
+}
+
 func (b *Backend) Workspaces() ([]string, error) {
-	return nil, backend.ErrWorkspacesNotSupported
+	names, err := b.client(backend.DefaultStateName).listChildren(b.workspaceKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	workspaces := []string{backend.DefaultStateName}
+	for _, name := range names {
+		workspaces = append(workspaces, strings.Trim(name, "/"))
+	}
+
+	sort.Strings(workspaces[1:])
+	return workspaces, nil
 }
 
-func (b *Backend) DeleteWorkspace(string) error {
-	return backend.ErrWorkspacesNotSupported
+func (b *Backend) DeleteWorkspace(name string) error {
+	if name == backend.DefaultStateName {
+		return fmt.Errorf("cannot delete default state")
+	}
+	return b.client(name).Delete()
 }
 
 func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
-	if name != backend.DefaultStateName {
-		return nil, backend.ErrWorkspacesNotSupported
-	}
 	return &remote.State{
-		Client: b.client,
+		Client: b.client(name),
 	}, nil
 }
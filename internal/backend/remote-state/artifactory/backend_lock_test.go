@@ -0,0 +1,104 @@
+package artifactory
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/states/statemgr"
+)
+
+func TestBackend_accessTokenAuth(t *testing.T) {
+	srv := artifactoryTestServer(t)
+	defer srv.Close()
+
+	var gotAuth string
+	srv.Config.Handler = captureAuthHeader(srv.Config.Handler, &gotAuth)
+
+	b := New().(*Backend)
+	diags := b.Configure(backend.TestWrapConfig(map[string]interface{}{
+		"access_token": "sekret-token",
+		"url":          srv.URL,
+		"repo":         "terraform-repo",
+		"subpath":      "terraform.tfstate",
+	}))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	if err := b.client(backend.DefaultStateName).Put([]byte("state-data")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if want := "Bearer sekret-token"; gotAuth != want {
+		t.Fatalf("wrong Authorization header %q; want %q", gotAuth, want)
+	}
+}
+
+func TestBackend_missingCredentials(t *testing.T) {
+	b := New().(*Backend)
+	diags := b.Configure(backend.TestWrapConfig(map[string]interface{}{
+		"url":     "https://artifactory.example.com/artifactory",
+		"repo":    "terraform-repo",
+		"subpath": "terraform.tfstate",
+	}))
+	if !diags.HasErrors() {
+		t.Fatal("expected an error when neither access_token nor username/password are set")
+	}
+}
+
+func TestBackend_lockContention(t *testing.T) {
+	srv := artifactoryTestServer(t)
+	defer srv.Close()
+
+	b := testBackend(t, srv)
+	client := b.client(backend.DefaultStateName)
+
+	id, err := client.Lock(&statemgr.LockInfo{Operation: "plan"})
+	if err != nil {
+		t.Fatalf("unexpected error acquiring first lock: %s", err)
+	}
+
+	other := b.client(backend.DefaultStateName)
+	if _, err := other.Lock(&statemgr.LockInfo{Operation: "apply"}); err == nil {
+		t.Fatal("expected second locker to fail to acquire the lock")
+	}
+
+	if err := client.Unlock(id); err != nil {
+		t.Fatalf("unexpected error releasing lock: %s", err)
+	}
+
+	// Now that the lock is released, another locker can acquire it.
+	if _, err := other.Lock(&statemgr.LockInfo{Operation: "apply"}); err != nil {
+		t.Fatalf("unexpected error acquiring lock after release: %s", err)
+	}
+}
+
+func TestBackend_forceUnlock(t *testing.T) {
+	srv := artifactoryTestServer(t)
+	defer srv.Close()
+
+	b := testBackend(t, srv)
+	client := b.client(backend.DefaultStateName)
+
+	id, err := client.Lock(&statemgr.LockInfo{Operation: "plan"})
+	if err != nil {
+		t.Fatalf("unexpected error acquiring lock: %s", err)
+	}
+
+	// Force-unlock uses the ID reported by the lock itself.
+	if err := b.client(backend.DefaultStateName).Unlock(id); err != nil {
+		t.Fatalf("unexpected error force-unlocking: %s", err)
+	}
+
+	if _, err := b.client(backend.DefaultStateName).Lock(&statemgr.LockInfo{Operation: "plan"}); err != nil {
+		t.Fatalf("expected lock to be free after force-unlock: %s", err)
+	}
+}
+
+func captureAuthHeader(next http.Handler, out *string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*out = r.Header.Get("Authorization")
+		next.ServeHTTP(w, r)
+	})
+}
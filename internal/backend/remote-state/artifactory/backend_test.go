@@ -0,0 +1,317 @@
+package artifactory
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/states/remote"
+)
+
+// artifactoryTestServer emulates just enough of the Artifactory REST API
+// (artifact PUT/GET/DELETE and the folder-info storage API) to exercise
+// the backend's workspace support.
+func artifactoryTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var mu sync.Mutex
+	artifacts := map[string][]byte{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/storage/", func(w http.ResponseWriter, r *http.Request) {
+		dir := strings.TrimPrefix(r.URL.Path, "/api/storage/")
+		dir = strings.Trim(dir, "/")
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		children := map[string]bool{} // name -> isFolder
+		for artifactPath := range artifacts {
+			artifactPath = strings.Trim(artifactPath, "/")
+			if dir != "" && !strings.HasPrefix(artifactPath, dir+"/") {
+				continue
+			}
+			rest := strings.TrimPrefix(artifactPath, dir+"/")
+			parts := strings.SplitN(rest, "/", 2)
+			if len(parts) == 2 {
+				children["/"+parts[0]] = true
+			} else if rest != "" {
+				children["/"+rest] = false
+			}
+		}
+
+		if len(children) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+
+		type child struct {
+			URI    string `json:"uri"`
+			Folder bool   `json:"folder"`
+		}
+		var resp struct {
+			Children []child `json:"children"`
+		}
+		for name, isFolder := range children {
+			resp.Children = append(resp.Children, child{URI: name, Folder: isFolder})
+		}
+
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		p := strings.TrimPrefix(r.URL.Path, "/")
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			if strings.HasSuffix(p, ".tflock") {
+				if _, exists := artifacts[p]; exists {
+					w.WriteHeader(http.StatusConflict)
+					return
+				}
+			}
+			body := make([]byte, r.ContentLength)
+			_, _ = r.Body.Read(body)
+			artifacts[p] = body
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			data, ok := artifacts[p]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(data)
+		case http.MethodDelete:
+			if _, ok := artifacts[p]; !ok {
+				http.NotFound(w, r)
+				return
+			}
+			delete(artifacts, p)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "unsupported method", http.StatusMethodNotAllowed)
+		}
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func testBackend(t *testing.T, srv *httptest.Server) *Backend {
+	t.Helper()
+	return testBackendWithConfig(t, srv, nil)
+}
+
+// testBackendWithConfig is like testBackend but lets a test override or add
+// to the base configuration, e.g. to set workspace_key_prefix.
+func testBackendWithConfig(t *testing.T, srv *httptest.Server, overrides map[string]interface{}) *Backend {
+	t.Helper()
+
+	config := map[string]interface{}{
+		"username": "user",
+		"password": "pass",
+		"url":      srv.URL,
+		"repo":     "terraform-repo",
+		"subpath":  "terraform.tfstate",
+	}
+	for k, v := range overrides {
+		config[k] = v
+	}
+
+	b := New().(*Backend)
+	diags := b.Configure(backend.TestWrapConfig(config))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	return b
+}
+
+// stateMgrClient extracts the ArtifactoryClient backing a statemgr.Full
+// returned by Backend.StateMgr, so a test can read/write through it
+// directly without depending on the states/remote package's own tests.
+func stateMgrClient(t *testing.T, b *Backend, workspace string) *ArtifactoryClient {
+	t.Helper()
+
+	mgr, err := b.StateMgr(workspace)
+	if err != nil {
+		t.Fatalf("unexpected error getting state manager for %q: %s", workspace, err)
+	}
+	rs, ok := mgr.(*remote.State)
+	if !ok {
+		t.Fatalf("StateMgr(%q) returned %T, not *remote.State", workspace, mgr)
+	}
+	client, ok := rs.Client.(*ArtifactoryClient)
+	if !ok {
+		t.Fatalf("remote.State.Client is %T, not *ArtifactoryClient", rs.Client)
+	}
+	return client
+}
+
+func TestBackend_workspaces(t *testing.T) {
+	srv := artifactoryTestServer(t)
+	defer srv.Close()
+
+	b := testBackend(t, srv)
+
+	workspaces, err := b.Workspaces()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(workspaces) != 1 || workspaces[0] != backend.DefaultStateName {
+		t.Fatalf("expected only the default workspace, got %v", workspaces)
+	}
+
+	if err := b.client("staging").Put([]byte("state-data")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	workspaces, err = b.Workspaces()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	found := false
+	for _, w := range workspaces {
+		if w == "staging" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected to find workspace %q, got %v", "staging", workspaces)
+	}
+}
+
+func TestBackend_deleteWorkspaceRejectsDefault(t *testing.T) {
+	srv := artifactoryTestServer(t)
+	defer srv.Close()
+
+	b := testBackend(t, srv)
+
+	if err := b.DeleteWorkspace(backend.DefaultStateName); err == nil {
+		t.Fatal("expected an error deleting the default workspace")
+	}
+}
+
+// TestBackend_stateMgrIsolation verifies that StateMgr for the default
+// workspace and StateMgr for a named workspace read and write distinct
+// artifacts, rather than accidentally sharing the same underlying path.
+func TestBackend_stateMgrIsolation(t *testing.T) {
+	srv := artifactoryTestServer(t)
+	defer srv.Close()
+
+	b := testBackend(t, srv)
+
+	defaultClient := stateMgrClient(t, b, backend.DefaultStateName)
+	stagingClient := stateMgrClient(t, b, "staging")
+
+	if err := defaultClient.Put([]byte("default-state")); err != nil {
+		t.Fatalf("unexpected error writing default state: %s", err)
+	}
+	if err := stagingClient.Put([]byte("staging-state")); err != nil {
+		t.Fatalf("unexpected error writing staging state: %s", err)
+	}
+
+	defaultPayload, err := defaultClient.Get()
+	if err != nil {
+		t.Fatalf("unexpected error reading default state: %s", err)
+	}
+	if string(defaultPayload.Data) != "default-state" {
+		t.Errorf("wrong default state data %q", defaultPayload.Data)
+	}
+
+	stagingPayload, err := stagingClient.Get()
+	if err != nil {
+		t.Fatalf("unexpected error reading staging state: %s", err)
+	}
+	if string(stagingPayload.Data) != "staging-state" {
+		t.Errorf("wrong staging state data %q", stagingPayload.Data)
+	}
+}
+
+// TestBackend_deleteWorkspace verifies that DeleteWorkspace on a non-default
+// workspace removes that workspace's artifact (and only that one), and that
+// the workspace subsequently drops out of Workspaces().
+func TestBackend_deleteWorkspace(t *testing.T) {
+	srv := artifactoryTestServer(t)
+	defer srv.Close()
+
+	b := testBackend(t, srv)
+
+	if err := b.client("staging").Put([]byte("staging-state")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := b.client(backend.DefaultStateName).Put([]byte("default-state")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := b.DeleteWorkspace("staging"); err != nil {
+		t.Fatalf("unexpected error deleting workspace: %s", err)
+	}
+
+	payload, err := b.client("staging").Get()
+	if err != nil {
+		t.Fatalf("unexpected error checking deleted workspace: %s", err)
+	}
+	if payload != nil {
+		t.Errorf("expected staging state to be deleted, got %q", payload.Data)
+	}
+
+	defaultPayload, err := b.client(backend.DefaultStateName).Get()
+	if err != nil {
+		t.Fatalf("unexpected error reading default state: %s", err)
+	}
+	if defaultPayload == nil || string(defaultPayload.Data) != "default-state" {
+		t.Errorf("default workspace state was affected by deleting staging: %#v", defaultPayload)
+	}
+
+	workspaces, err := b.Workspaces()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, w := range workspaces {
+		if w == "staging" {
+			t.Fatalf("expected \"staging\" to be gone from Workspaces(), got %v", workspaces)
+		}
+	}
+}
+
+// TestBackend_workspaceKeyPrefix verifies that a custom workspace_key_prefix
+// changes where non-default workspace state is stored, which existing
+// users migrating from another backend (e.g. S3) rely on to match their
+// prior layout.
+func TestBackend_workspaceKeyPrefix(t *testing.T) {
+	srv := artifactoryTestServer(t)
+	defer srv.Close()
+
+	b := testBackendWithConfig(t, srv, map[string]interface{}{
+		workspaceKeyPrefixKey: "workspaces",
+	})
+
+	if err := b.client("staging").Put([]byte("staging-state")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	client := stateMgrClient(t, b, "staging")
+	if !strings.HasPrefix(client.subpath, "workspaces/staging/") {
+		t.Errorf("expected subpath to use the custom workspace_key_prefix, got %q", client.subpath)
+	}
+
+	workspaces, err := b.Workspaces()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	found := false
+	for _, w := range workspaces {
+		if w == "staging" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected to find workspace %q under the custom prefix, got %v", "staging", workspaces)
+	}
+}
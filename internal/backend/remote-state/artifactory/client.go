@@ -0,0 +1,238 @@
+package artifactory
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/states/remote"
+	"github.com/hashicorp/terraform/internal/states/statemgr"
+	artifactory "github.com/lusis/go-artifactory/src/artifactory.v401"
+)
+
+// ArtifactoryClient implements remote.Client and statemgr.Locker, storing
+// state (and, alongside it, a companion lock artifact) in an Artifactory
+// repository.
+type ArtifactoryClient struct {
+	nativeClient *artifactory.Artifactory
+	httpClient   *http.Client
+	userName     string
+	password     string
+	accessToken  string
+	url          string
+	repo         string
+	subpath      string
+}
+
+func (c *ArtifactoryClient) Get() (*remote.Payload, error) {
+	p := url.Values{}
+	data, err := c.nativeClient.Get(fmt.Sprintf("%s/%s", c.repo, c.subpath), p)
+	if err != nil {
+		// 404 means no state has been stored at this path yet.
+		if err.Error() == "404 Not Found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	return &remote.Payload{
+		Data: data,
+	}, nil
+}
+
+func (c *ArtifactoryClient) Put(data []byte) error {
+	params := url.Values{}
+	return c.nativeClient.Put(fmt.Sprintf("%s/%s", c.repo, c.subpath), params, bytes.NewReader(data), int64(len(data)))
+}
+
+func (c *ArtifactoryClient) Delete() error {
+	return c.nativeClient.Delete(fmt.Sprintf("%s/%s", c.repo, c.subpath))
+}
+
+// lockSubpath is the path of the companion artifact used to hold the
+// current lock, alongside the state artifact at c.subpath.
+func (c *ArtifactoryClient) lockSubpath() string {
+	return c.subpath + ".tflock"
+}
+
+func (c *ArtifactoryClient) artifactURL(subpath string) string {
+	return strings.TrimSuffix(c.url, "/") + "/" + c.repo + "/" + subpath
+}
+
+func (c *ArtifactoryClient) newRequest(method, subpath string, body []byte) (*http.Request, error) {
+	var reader *bytes.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, c.artifactURL(subpath), reader)
+	if err != nil {
+		return nil, err
+	}
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	} else if c.userName != "" {
+		req.SetBasicAuth(c.userName, c.password)
+	}
+	return req, nil
+}
+
+// Lock implements statemgr.Locker by performing an atomic create of the
+// lock artifact: Artifactory rejects a PUT of an artifact that already
+// exists with a 409, which we treat as "somebody else holds the lock".
+func (c *ArtifactoryClient) Lock(info *statemgr.LockInfo) (string, error) {
+	if info.ID == "" {
+		id, err := generateLockID()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate lock ID: %w", err)
+		}
+		info.ID = id
+	}
+	info.Path = c.lockSubpath()
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal lock info: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPut, c.lockSubpath(), data)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return info.ID, nil
+	case http.StatusConflict:
+		existing, getErr := c.getLockInfo()
+		if getErr != nil {
+			existing = info
+		}
+		return "", &statemgr.LockError{
+			Info: existing,
+			Err:  fmt.Errorf("state is already locked"),
+		}
+	default:
+		return "", fmt.Errorf("failed to lock state: unexpected status %s", resp.Status)
+	}
+}
+
+// Unlock implements statemgr.Locker. The lock is released by deleting the
+// lock artifact; if id does not match the holder of the current lock the
+// unlock is rejected, except when force-unlocking with the ID reported by
+// the lock artifact itself.
+func (c *ArtifactoryClient) Unlock(id string) error {
+	existing, err := c.getLockInfo()
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return &statemgr.LockError{
+			Info: existing,
+			Err:  fmt.Errorf("lock id %q does not match existing lock id %q", id, existing.ID),
+		}
+	}
+
+	req, err := c.newRequest(http.MethodDelete, c.lockSubpath(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("failed to unlock state: unexpected status %s", resp.Status)
+	}
+}
+
+func (c *ArtifactoryClient) getLockInfo() (*statemgr.LockInfo, error) {
+	req, err := c.newRequest(http.MethodGet, c.lockSubpath(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read lock info: unexpected status %s", resp.Status)
+	}
+
+	var info statemgr.LockInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to parse lock info: %w", err)
+	}
+	return &info, nil
+}
+
+func generateLockID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", buf), nil
+}
+
+// folderChild describes a single entry returned by Artifactory's folder
+// info API (GET /api/storage/<repo>/<path>).
+type folderChild struct {
+	URI    string `json:"uri"`
+	Folder bool   `json:"folder"`
+}
+
+type folderInfo struct {
+	Children []folderChild `json:"children"`
+}
+
+// listChildren returns the names of the sub-folders directly contained in
+// the repository folder at the given path. It returns an empty slice,
+// rather than an error, when the folder does not exist yet.
+func (c *ArtifactoryClient) listChildren(path string) ([]string, error) {
+	data, err := c.nativeClient.Get(fmt.Sprintf("api/storage/%s/%s", c.repo, path), url.Values{})
+	if err != nil {
+		if err.Error() == "404 Not Found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var info folderInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse artifactory folder info for %q: %w", path, err)
+	}
+
+	var names []string
+	for _, child := range info.Children {
+		if !child.Folder {
+			continue
+		}
+		names = append(names, child.URI)
+	}
+	return names, nil
+}
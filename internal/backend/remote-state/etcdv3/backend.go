@@ -2,6 +2,8 @@ package etcd
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform/internal/backend"
 	"github.com/hashicorp/terraform/internal/legacy/helper/schema"
@@ -21,6 +23,12 @@ const (
 	cacertPathKey      = "cacert_path"
 	certPathKey        = "cert_path"
 	keyPathKey         = "key_path"
+	tlsServerNameKey   = "tls_server_name"
+
+	dialTimeoutKey          = "dial_timeout"
+	dialKeepAliveTimeKey    = "dial_keepalive_time"
+	dialKeepAliveTimeoutKey = "dial_keepalive_timeout"
+	maxReceiveBytesKey      = "max_receive_bytes"
 )
 
 func New() backend.Backend {
@@ -91,6 +99,44 @@ func New() backend.Backend {
 				Description: "The path to a PEM-encoded key to provide to etcd for secure client identification.",
 				Default:     "",
 			},
+
+			tlsServerNameKey: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The ServerName to use to verify the hostname on the returned certificates from the etcd cluster.",
+				Default:     "",
+			},
+
+			dialTimeoutKey: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The timeout for failing to establish a connection to the etcd cluster.",
+				Default:      "",
+				ValidateFunc: validateDuration,
+			},
+
+			dialKeepAliveTimeKey: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The time after which the client pings the etcd cluster to check if the transport is alive.",
+				Default:      "",
+				ValidateFunc: validateDuration,
+			},
+
+			dialKeepAliveTimeoutKey: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The time that the client waits for a response for the keepalive probe before considering the connection dead.",
+				Default:      "",
+				ValidateFunc: validateDuration,
+			},
+
+			maxReceiveBytesKey: {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The max receive message size in bytes the client can receive from the etcd cluster.",
+				Default:     0,
+			},
 		},
 	}
 
@@ -117,12 +163,42 @@ func (b *Backend) configure(ctx context.Context) error {
 	b.lock = b.data.Get(lockKey).(bool)
 	// Store the prefix information.
 	b.prefix = b.data.Get(prefixKey).(string)
+	// Reject TLS field combinations that rawClient can't turn into a
+	// coherent transport.TLSInfo before we ever dial the cluster.
+	if err := b.validateTLSFields(); err != nil {
+		return err
+	}
 	// Initialize a client to test config.
 	b.client, err = b.rawClient()
 	// Return err, if any.
 	return err
 }
 
+// validateTLSFields checks that the cacert_path, cert_path, key_path, and
+// tls_server_name fields are set in mutually consistent combinations.
+func (b *Backend) validateTLSFields() error {
+	return tlsFieldsConsistent(
+		b.data.Get(cacertPathKey).(string),
+		b.data.Get(certPathKey).(string),
+		b.data.Get(keyPathKey).(string),
+		b.data.Get(tlsServerNameKey).(string),
+	)
+}
+
+// tlsFieldsConsistent checks that cert_path and key_path are a matched pair,
+// and that tls_server_name is only set once at least one other TLS field
+// establishes that TLS is in use, since it otherwise has no effect. These
+// are cross-field rules that a single-field ValidateFunc can't express.
+func tlsFieldsConsistent(cacertPath, certPath, keyPath, tlsServerName string) error {
+	if (certPath != "") != (keyPath != "") {
+		return fmt.Errorf("%q and %q must both be set, or both be empty", certPathKey, keyPathKey)
+	}
+	if tlsServerName != "" && cacertPath == "" && certPath == "" {
+		return fmt.Errorf("%q has no effect unless %q or %q/%q is also set", tlsServerNameKey, cacertPathKey, certPathKey, keyPathKey)
+	}
+	return nil
+}
+
 func (b *Backend) rawClient() (*etcdv3.Client, error) {
 	config := etcdv3.Config{}
 	tlsInfo := transport.TLSInfo{}
@@ -148,6 +224,33 @@ func (b *Backend) rawClient() (*etcdv3.Client, error) {
 	if v, ok := b.data.GetOk(keyPathKey); ok && v.(string) != "" {
 		tlsInfo.KeyFile = v.(string)
 	}
+	if v, ok := b.data.GetOk(tlsServerNameKey); ok && v.(string) != "" {
+		tlsInfo.ServerName = v.(string)
+	}
+	if v, ok := b.data.GetOk(dialTimeoutKey); ok && v.(string) != "" {
+		d, err := time.ParseDuration(v.(string))
+		if err != nil {
+			return nil, err
+		}
+		config.DialTimeout = d
+	}
+	if v, ok := b.data.GetOk(dialKeepAliveTimeKey); ok && v.(string) != "" {
+		d, err := time.ParseDuration(v.(string))
+		if err != nil {
+			return nil, err
+		}
+		config.DialKeepAliveTime = d
+	}
+	if v, ok := b.data.GetOk(dialKeepAliveTimeoutKey); ok && v.(string) != "" {
+		d, err := time.ParseDuration(v.(string))
+		if err != nil {
+			return nil, err
+		}
+		config.DialKeepAliveTimeout = d
+	}
+	if v, ok := b.data.GetOk(maxReceiveBytesKey); ok && v.(int) != 0 {
+		config.MaxCallRecvMsgSize = v.(int)
+	}
 
 	if tlsCfg, err := tlsInfo.ClientConfig(); err != nil {
 		return nil, err
@@ -158,6 +261,25 @@ func (b *Backend) rawClient() (*etcdv3.Client, error) {
 	return etcdv3.New(config)
 }
 
+// validateDuration checks that the given value, if set, parses as a
+// positive time.Duration (e.g. "5s", "500ms").
+func validateDuration(i interface{}, k string) (ws []string, errors []error) {
+	v, ok := i.(string)
+	if !ok || v == "" {
+		return nil, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q: %s", k, err))
+		return
+	}
+	if d <= 0 {
+		errors = append(errors, fmt.Errorf("%q must be a positive duration, got %q", k, v))
+	}
+	return
+}
+
 func retrieveEndpoints(v interface{}) []string {
 	var endpoints []string
 	list := v.([]interface{})
@@ -0,0 +1,70 @@
+package etcd
+
+import "testing"
+
+func TestValidateDuration(t *testing.T) {
+	cases := []struct {
+		Value string
+		Valid bool
+	}{
+		{"", true},
+		{"5s", true},
+		{"500ms", true},
+		{"-5s", false},
+		{"0s", false},
+		{"not-a-duration", false},
+	}
+
+	for _, tc := range cases {
+		_, errs := validateDuration(tc.Value, "dial_timeout")
+		if tc.Valid && len(errs) != 0 {
+			t.Errorf("validateDuration(%q) unexpected errors: %v", tc.Value, errs)
+		}
+		if !tc.Valid && len(errs) == 0 {
+			t.Errorf("validateDuration(%q) expected an error, got none", tc.Value)
+		}
+	}
+}
+
+func TestTLSFieldsConsistent(t *testing.T) {
+	cases := []struct {
+		Name                                         string
+		CacertPath, CertPath, KeyPath, TLSServerName string
+		Valid                                        bool
+	}{
+		{"all empty", "", "", "", "", true},
+		{"cacert only", "ca.pem", "", "", "", true},
+		{"cert and key", "", "cert.pem", "key.pem", "", true},
+		{"server name with cacert", "ca.pem", "", "", "etcd.example.com", true},
+		{"server name with cert/key", "", "cert.pem", "key.pem", "etcd.example.com", true},
+		{"cert without key", "", "cert.pem", "", "", false},
+		{"key without cert", "", "", "key.pem", "", false},
+		{"server name with no TLS material", "", "", "", "etcd.example.com", false},
+	}
+
+	for _, tc := range cases {
+		err := tlsFieldsConsistent(tc.CacertPath, tc.CertPath, tc.KeyPath, tc.TLSServerName)
+		if tc.Valid && err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.Name, err)
+		}
+		if !tc.Valid && err == nil {
+			t.Errorf("%s: expected an error, got none", tc.Name)
+		}
+	}
+}
+
+func TestBackend_newFieldsInSchema(t *testing.T) {
+	s := New().(*Backend).Backend.Schema
+
+	for _, key := range []string{
+		tlsServerNameKey,
+		dialTimeoutKey,
+		dialKeepAliveTimeKey,
+		dialKeepAliveTimeoutKey,
+		maxReceiveBytesKey,
+	} {
+		if _, ok := s[key]; !ok {
+			t.Errorf("expected schema to contain %q", key)
+		}
+	}
+}
@@ -0,0 +1,503 @@
+// Package cliconfig is responsible for loading the CLI configuration, which
+// is an optional config file and other Terraform-specific files that
+// customize the behavior of the command line interface globally, as opposed
+// to what goes into a module directory.
+//
+// The constructs in this package are for internal use only and are not
+// covered by the Terraform v1.x compatibility promises.
+package cliconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	svchost "github.com/hashicorp/terraform-svchost"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// Config is the structure of the configuration for the Terraform CLI.
+//
+// This is not the configuration for Terraform itself. That is in the
+// "config" package.
+type Config struct {
+	Providers    map[string]string
+	Provisioners map[string]string
+
+	DisableCheckpoint          bool `hcl:"disable_checkpoint"`
+	DisableCheckpointSignature bool `hcl:"disable_checkpoint_signature"`
+
+	// If set, enables local caching of plugins in this directory to
+	// avoid re-downloading over multiple runs.
+	PluginCacheDir string `hcl:"plugin_cache_dir"`
+
+	Hosts map[string]*ConfigHost
+
+	Credentials        map[string]map[string]interface{}
+	CredentialsHelpers map[string]*ConfigCredentialsHelper
+
+	// Logins are the contents of zero or more "login" blocks, each one
+	// overriding the OAuth client id Terraform presents in the device
+	// authorization flow against a particular host. Hosts not present in
+	// this map use the client id advertised by their login.v1 service
+	// discovery entry; the endpoints always come from that entry.
+	Logins map[string]*ConfigLogin
+
+	// ProviderInstallation represents any provider_installation blocks in
+	// the configuration. Only one of these is allowed across the whole
+	// configuration, but we decode into a slice here so that we can handle
+	// that validation at validation time rather than initial decode time.
+	ProviderInstallation []*ProviderInstallation
+}
+
+// ConfigHost is a structure representing a particular configured host
+// block, which modifies how Terraform interacts with a particular
+// service hostname.
+type ConfigHost struct {
+	Services map[string]interface{} `hcl:"services"`
+}
+
+// ConfigCredentialsHelper is the structure of the "credentials_helper"
+// nested block within the CLI configuration.
+type ConfigCredentialsHelper struct {
+	Args []string `hcl:"args"`
+}
+
+// ConfigLogin is the structure of the "login" nested block within the CLI
+// configuration, allowing a user to override the OAuth client id Terraform
+// presents when running "terraform login" against a particular host.
+type ConfigLogin struct {
+	ClientID string `hcl:"client_id"`
+}
+
+// BuiltinConfig is the built-in defaults for the configuration. These
+// can be overridden by user configurations.
+var BuiltinConfig Config
+
+// ConfigFile returns the default path to the configuration file.
+//
+// This is the "terraformrc" file in the configuration directory: the
+// ".terraform.d" directory in the user's home directory, or the directory
+// containing TF_CLI_CONFIG_FILE when that variable is set. The same path is
+// used on every platform.
+func ConfigFile() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, configFileName), nil
+}
+
+// ConfigDir returns the configuration directory for Terraform.
+func ConfigDir() (string, error) {
+	return configDir()
+}
+
+// LoadConfig reads the CLI configuration from the various well-known places
+// where it might be set -- the user's home directory, TF_CLI_CONFIG_FILE,
+// etc -- and merges it with the built-in defaults, returning the result.
+func LoadConfig() (*Config, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	result := &BuiltinConfig
+
+	if mainFilename, err := cliConfigFile(); err == nil {
+		if _, err := os.Stat(mainFilename); err == nil {
+			mainConfig, mainDiags := loadConfigFile(mainFilename)
+			diags = diags.Append(mainDiags)
+			result = result.Merge(mainConfig)
+		}
+	} else {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Cannot establish location of the Terraform CLI configuration file",
+			fmt.Sprintf("While attempting to detect the location of your Terraform CLI configuration file, an error occurred: %s", err),
+		))
+	}
+
+	if fileCreds, err := loadCredentialsFile(); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Cannot read Terraform credentials file",
+			fmt.Sprintf("While attempting to read credentials acquired by a previous \"terraform login\", an error occurred: %s", err),
+		))
+	} else if len(fileCreds) > 0 {
+		merged := make(map[string]map[string]interface{}, len(result.Credentials)+len(fileCreds))
+		for host, creds := range result.Credentials {
+			merged[host] = creds
+		}
+		for host, creds := range fileCreds {
+			merged[host] = creds
+		}
+		result.Credentials = merged
+	}
+
+	result.refreshExpiringCredentials(oauthRefreshHTTPClient)
+
+	return result, diags
+}
+
+// loadConfigFile loads the CLI configuration from a given file.
+//
+// If the file can't be read, including when it doesn't exist, an empty
+// configuration is returned along with an error diagnostic.
+func loadConfigFile(path string) (*Config, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	result := &Config{}
+
+	d, err := ioutil.ReadFile(path)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot read CLI configuration file",
+			fmt.Sprintf("The CLI configuration file %q could not be read: %s", path, err),
+		))
+		return result, diags
+	}
+
+	obj, err := hcl.Parse(string(d))
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot parse CLI configuration file",
+			fmt.Sprintf("The CLI configuration file %q could not be parsed: %s", path, err),
+		))
+		return result, diags
+	}
+
+	// Decode the simple, directly-HCL-decodable parts first.
+	var raw struct {
+		Providers                  map[string]string `hcl:"providers"`
+		Provisioners               map[string]string `hcl:"provisioners"`
+		DisableCheckpoint          bool              `hcl:"disable_checkpoint"`
+		DisableCheckpointSignature bool              `hcl:"disable_checkpoint_signature"`
+		PluginCacheDir             string            `hcl:"plugin_cache_dir"`
+	}
+	if err := hcl.Decode(&raw, string(d)); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot parse CLI configuration file",
+			fmt.Sprintf("The CLI configuration file %q could not be parsed: %s", path, err),
+		))
+		return result, diags
+	}
+	result.Providers = raw.Providers
+	result.Provisioners = raw.Provisioners
+	// Provider and provisioner paths may reference environment variables
+	// (e.g. "$HOME/bin/terraform-provider-foo"), so expand those now.
+	for k, v := range result.Providers {
+		result.Providers[k] = os.ExpandEnv(v)
+	}
+	for k, v := range result.Provisioners {
+		result.Provisioners[k] = os.ExpandEnv(v)
+	}
+	result.DisableCheckpoint = raw.DisableCheckpoint
+	result.DisableCheckpointSignature = raw.DisableCheckpointSignature
+	result.PluginCacheDir = raw.PluginCacheDir
+
+	root, ok := obj.Node.(*ast.ObjectList)
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot parse CLI configuration file",
+			fmt.Sprintf("The CLI configuration file %q is invalid.", path),
+		))
+		return result, diags
+	}
+
+	// host, credentials, credentials_helper, and login blocks are all
+	// labelled with what can be an arbitrary user-specified string (often
+	// containing dots, as hostnames do) and so we must walk the AST
+	// directly for these, rather than relying on hcl.DecodeObject's
+	// struct-field-based mapping.
+	for _, item := range root.Items {
+		if len(item.Keys) == 0 {
+			continue
+		}
+		blockType := item.Keys[0].Token.Value().(string)
+
+		switch blockType {
+		case "host":
+			if len(item.Keys) < 2 {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid host block",
+					fmt.Sprintf("The host block at %s must have a hostname label.", item.Pos()),
+				))
+				continue
+			}
+			name := item.Keys[1].Token.Value().(string)
+			var host ConfigHost
+			if err := hcl.DecodeObject(&host, item.Val); err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid host block",
+					fmt.Sprintf("The host block for %q is invalid: %s", name, err),
+				))
+				continue
+			}
+			if result.Hosts == nil {
+				result.Hosts = make(map[string]*ConfigHost)
+			}
+			result.Hosts[name] = &host
+
+		case "credentials":
+			if len(item.Keys) < 2 {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid credentials block",
+					fmt.Sprintf("The credentials block at %s must have a hostname label.", item.Pos()),
+				))
+				continue
+			}
+			name := item.Keys[1].Token.Value().(string)
+			var creds map[string]interface{}
+			if err := hcl.DecodeObject(&creds, item.Val); err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid credentials block",
+					fmt.Sprintf("The credentials block for %q is invalid: %s", name, err),
+				))
+				continue
+			}
+			if result.Credentials == nil {
+				result.Credentials = make(map[string]map[string]interface{})
+			}
+			result.Credentials[name] = creds
+
+		case "credentials_helper":
+			if len(item.Keys) < 2 {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid credentials_helper block",
+					fmt.Sprintf("The credentials_helper block at %s must have a name label.", item.Pos()),
+				))
+				continue
+			}
+			name := item.Keys[1].Token.Value().(string)
+			var helper ConfigCredentialsHelper
+			if err := hcl.DecodeObject(&helper, item.Val); err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid credentials_helper block",
+					fmt.Sprintf("The credentials_helper block for %q is invalid: %s", name, err),
+				))
+				continue
+			}
+			if result.CredentialsHelpers == nil {
+				result.CredentialsHelpers = make(map[string]*ConfigCredentialsHelper)
+			}
+			result.CredentialsHelpers[name] = &helper
+
+		case "login":
+			if len(item.Keys) < 2 {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid login block",
+					fmt.Sprintf("The login block at %s must have a hostname label.", item.Pos()),
+				))
+				continue
+			}
+			name := item.Keys[1].Token.Value().(string)
+			var login ConfigLogin
+			if err := hcl.DecodeObject(&login, item.Val); err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid login block",
+					fmt.Sprintf("The login block for %q is invalid: %s", name, err),
+				))
+				continue
+			}
+			if result.Logins == nil {
+				result.Logins = make(map[string]*ConfigLogin)
+			}
+			result.Logins[name] = &login
+
+		case "provider_installation":
+			installations, err := parseProviderInstallationFromObject(item)
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid provider_installation block",
+					fmt.Sprintf("The provider_installation block at %s is invalid: %s", item.Pos(), err),
+				))
+				continue
+			}
+			result.ProviderInstallation = append(result.ProviderInstallation, installations...)
+		}
+	}
+
+	return result, diags
+}
+
+// Validate checks for errors in the configuration that cannot be detected
+// just by HCL decoding, returning a set of warnings and errors.
+func (c *Config) Validate() tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if c == nil {
+		return diags
+	}
+
+	for givenHost := range c.Hosts {
+		if _, err := svchost.ForComparison(givenHost); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid host in CLI configuration",
+				fmt.Sprintf("The host %q block has an invalid hostname: %s", givenHost, err),
+			))
+		}
+	}
+
+	for givenHost := range c.Credentials {
+		if _, err := svchost.ForComparison(givenHost); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid host in CLI configuration",
+				fmt.Sprintf("The credentials %q block has an invalid hostname: %s", givenHost, err),
+			))
+		}
+	}
+
+	for givenHost := range c.Logins {
+		if _, err := svchost.ForComparison(givenHost); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid host in CLI configuration",
+				fmt.Sprintf("The login %q block has an invalid hostname: %s", givenHost, err),
+			))
+		}
+	}
+
+	if len(c.CredentialsHelpers) > 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid CLI configuration",
+			"No more than one credentials_helper block may be specified.",
+		))
+	}
+
+	if len(c.ProviderInstallation) > 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid CLI configuration",
+			"No more than one provider_installation block may be specified.",
+		))
+	}
+
+	if c.PluginCacheDir != "" {
+		if _, err := os.Stat(c.PluginCacheDir); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid plugin cache directory",
+				fmt.Sprintf("The specified plugin cache dir %s cannot be opened: %s", c.PluginCacheDir, err),
+			))
+		}
+	}
+
+	return diags
+}
+
+// Merge merges two configurations and returns a third entirely
+// new configuration with the two merged.
+func (c1 *Config) Merge(c2 *Config) *Config {
+	result := new(Config)
+	result.Providers = make(map[string]string)
+	result.Provisioners = make(map[string]string)
+	for k, v := range c1.Providers {
+		result.Providers[k] = v
+	}
+	for k, v := range c2.Providers {
+		result.Providers[k] = v
+	}
+	for k, v := range c1.Provisioners {
+		result.Provisioners[k] = v
+	}
+	for k, v := range c2.Provisioners {
+		result.Provisioners[k] = v
+	}
+	result.Hosts = make(map[string]*ConfigHost)
+	for k, v := range c1.Hosts {
+		result.Hosts[k] = v
+	}
+	for k, v := range c2.Hosts {
+		result.Hosts[k] = v
+	}
+	result.Credentials = make(map[string]map[string]interface{})
+	for k, v := range c1.Credentials {
+		result.Credentials[k] = v
+	}
+	for k, v := range c2.Credentials {
+		result.Credentials[k] = v
+	}
+	result.CredentialsHelpers = make(map[string]*ConfigCredentialsHelper)
+	for k, v := range c1.CredentialsHelpers {
+		result.CredentialsHelpers[k] = v
+	}
+	for k, v := range c2.CredentialsHelpers {
+		result.CredentialsHelpers[k] = v
+	}
+	result.Logins = make(map[string]*ConfigLogin)
+	for k, v := range c1.Logins {
+		result.Logins[k] = v
+	}
+	for k, v := range c2.Logins {
+		result.Logins[k] = v
+	}
+
+	result.ProviderInstallation = append(result.ProviderInstallation, c1.ProviderInstallation...)
+	result.ProviderInstallation = append(result.ProviderInstallation, c2.ProviderInstallation...)
+
+	result.DisableCheckpoint = c1.DisableCheckpoint || c2.DisableCheckpoint
+	result.DisableCheckpointSignature = c1.DisableCheckpointSignature || c2.DisableCheckpointSignature
+
+	if c1.PluginCacheDir != "" {
+		result.PluginCacheDir = c1.PluginCacheDir
+	} else {
+		result.PluginCacheDir = c2.PluginCacheDir
+	}
+
+	if len(result.Hosts) == 0 {
+		result.Hosts = nil
+	}
+	if len(result.Credentials) == 0 {
+		result.Credentials = nil
+	}
+	if len(result.CredentialsHelpers) == 0 {
+		result.CredentialsHelpers = nil
+	}
+	if len(result.Logins) == 0 {
+		result.Logins = nil
+	}
+
+	return result
+}
+
+// configDir returns the configuration directory for Terraform.
+func configDir() (string, error) {
+	if v := os.Getenv("TF_CLI_CONFIG_FILE"); v != "" {
+		return filepath.Dir(v), nil
+	}
+
+	dir, err := homedir.Dir()
+	if err != nil {
+		return "", fmt.Errorf("failed to detect the user's home directory: %w", err)
+	}
+
+	return filepath.Join(dir, ".terraform.d"), nil
+}
+
+// cliConfigFile returns the path to the main CLI configuration file,
+// honoring the TF_CLI_CONFIG_FILE environment variable override.
+func cliConfigFile() (string, error) {
+	if v := os.Getenv("TF_CLI_CONFIG_FILE"); v != "" {
+		return v, nil
+	}
+	return ConfigFile()
+}
+
+const configFileName = "terraformrc"
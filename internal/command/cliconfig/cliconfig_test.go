@@ -1,6 +1,7 @@
 package cliconfig
 
 import (
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -104,6 +105,49 @@ func TestLoadConfig_credentials(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_oauthRefresh(t *testing.T) {
+	defer func(orig func(*http.Client, string, string, string) (*oauthToken, error)) {
+		oauthRefreshFunc = orig
+	}(oauthRefreshFunc)
+
+	var gotTokenURL, gotClientID, gotRefreshToken string
+	oauthRefreshFunc = func(client *http.Client, tokenURL, clientID, refreshToken string) (*oauthToken, error) {
+		gotTokenURL = tokenURL
+		gotClientID = clientID
+		gotRefreshToken = refreshToken
+		return &oauthToken{
+			AccessToken:  "new-access-token",
+			RefreshToken: "new-refresh-token",
+			ExpiresIn:    3600,
+		}, nil
+	}
+
+	c, diags := loadConfigFile(filepath.Join(fixtureDir, "oauth-refresh"))
+	if len(diags) != 0 {
+		t.Fatalf("%s", diags.Err())
+	}
+	c.refreshExpiringCredentials(http.DefaultClient)
+
+	if gotTokenURL != "https://example.com/oauth/token" {
+		t.Errorf("wrong token URL %q", gotTokenURL)
+	}
+	if gotClientID != "terraform-cli" {
+		t.Errorf("wrong client id %q", gotClientID)
+	}
+	if gotRefreshToken != "old-refresh-token" {
+		t.Errorf("wrong refresh token %q", gotRefreshToken)
+	}
+
+	got := c.Credentials["example.com"]["token"]
+	if got != "new-access-token" {
+		t.Errorf("wrong refreshed token %#v", got)
+	}
+	gotRefresh := c.Credentials["example.com"]["refresh_token"]
+	if gotRefresh != "new-refresh-token" {
+		t.Errorf("wrong rotated refresh token %#v", gotRefresh)
+	}
+}
+
 func TestConfigValidate(t *testing.T) {
 	tests := map[string]struct {
 		Config    *Config
@@ -153,6 +197,22 @@ func TestConfigValidate(t *testing.T) {
 			},
 			1, // credentials block has invalid hostname
 		},
+		"login good": {
+			&Config{
+				Logins: map[string]*ConfigLogin{
+					"example.com": {ClientID: "terraform-cli"},
+				},
+			},
+			0,
+		},
+		"login with bad hostname": {
+			&Config{
+				Logins: map[string]*ConfigLogin{
+					"example..com": {ClientID: "terraform-cli"},
+				},
+			},
+			1, // login block has invalid hostname
+		},
 		"credentials helper good": {
 			&Config{
 				CredentialsHelpers: map[string]*ConfigCredentialsHelper{
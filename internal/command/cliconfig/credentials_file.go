@@ -0,0 +1,94 @@
+package cliconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// credentialsFileName is the JSON file, alongside the main CLI configuration
+// file in the configuration directory, where Terraform persists credentials
+// it has acquired itself (currently just via the device authorization login
+// flow). Hand-written "credentials" blocks live in the main configuration
+// file instead; keeping acquired credentials separate means logging in
+// never requires rewriting a file the user maintains by hand.
+const credentialsFileName = "credentials.tfrc.json"
+
+// credentialsFile is the on-disk JSON structure of credentialsFileName.
+type credentialsFile struct {
+	Credentials map[string]map[string]interface{} `json:"credentials"`
+}
+
+// credentialsFilePath returns the path to the JSON credentials file,
+// honoring the same TF_CLI_CONFIG_FILE-relative directory as ConfigFile.
+func credentialsFilePath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, credentialsFileName), nil
+}
+
+// loadCredentialsFile reads the JSON credentials file, if any, and returns
+// the host-to-credentials map it contains. A missing file is not an error.
+func loadCredentialsFile() (map[string]map[string]interface{}, error) {
+	path, err := credentialsFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot read credentials file %q: %w", path, err)
+	}
+
+	var f credentialsFile
+	if err := json.Unmarshal(d, &f); err != nil {
+		return nil, fmt.Errorf("cannot parse credentials file %q: %w", path, err)
+	}
+	return f.Credentials, nil
+}
+
+// SetCredentials records the given credentials for host in the JSON
+// credentials file, creating the file and its directory if necessary, and
+// updates c.Credentials in memory to match. It's used by the device
+// authorization login flow to persist an acquired token without disturbing
+// any hand-written "credentials" blocks in the main configuration file.
+func (c *Config) SetCredentials(host string, creds map[string]interface{}) error {
+	path, err := credentialsFilePath()
+	if err != nil {
+		return err
+	}
+
+	existing, err := loadCredentialsFile()
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		existing = make(map[string]map[string]interface{})
+	}
+	existing[host] = creds
+
+	d, err := json.MarshalIndent(credentialsFile{Credentials: existing}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize credentials: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory for credentials file %q: %w", path, err)
+	}
+	if err := ioutil.WriteFile(path, d, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials file %q: %w", path, err)
+	}
+
+	if c.Credentials == nil {
+		c.Credentials = make(map[string]map[string]interface{})
+	}
+	c.Credentials[host] = creds
+	return nil
+}
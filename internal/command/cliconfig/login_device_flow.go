@@ -0,0 +1,291 @@
+package cliconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// deviceCodeGrantType is the grant_type value for RFC 8628 (OAuth 2.0
+// Device Authorization Grant) that a host's login.v1 service discovery
+// document must advertise in its grant_types list before Terraform will
+// attempt a device-code login against it.
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// LoginService describes the subset of a host's login.v1 service discovery
+// document that the device authorization flow needs: where to request a
+// device code, where to poll for a token, and which client id to present.
+type LoginService struct {
+	ClientID   string
+	AuthzURL   string
+	TokenURL   string
+	GrantTypes []string
+}
+
+// SupportsDeviceCode reports whether the host's login.v1 service
+// advertises the device_code grant type required for this flow.
+func (s LoginService) SupportsDeviceCode() bool {
+	for _, gt := range s.GrantTypes {
+		if gt == deviceCodeGrantType {
+			return true
+		}
+	}
+	return false
+}
+
+// DeviceAuthorization is the response from a host's device authorization
+// endpoint, as defined by RFC 8628 section 3.2.
+type DeviceAuthorization struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// oauthToken is the subset of an OAuth 2.0 token response that Terraform
+// reads. Every field except TokenType is persisted to the credentials file.
+type oauthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	TokenType    string `json:"token_type"`
+}
+
+// RequestDeviceAuthorization starts a device authorization grant by
+// POSTing to the host's device-authz endpoint, as described by RFC 8628
+// section 3.1.
+func RequestDeviceAuthorization(httpClient *http.Client, svc LoginService) (*DeviceAuthorization, error) {
+	if !svc.SupportsDeviceCode() {
+		return nil, fmt.Errorf("host's login service does not support device code authorization")
+	}
+
+	resp, err := httpClient.PostForm(svc.AuthzURL, url.Values{
+		"client_id": {svc.ClientID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device authorization: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("device authorization request failed: %s", resp.Status)
+	}
+
+	var da DeviceAuthorization
+	if err := json.NewDecoder(resp.Body).Decode(&da); err != nil {
+		return nil, fmt.Errorf("failed to parse device authorization response: %w", err)
+	}
+	if da.Interval <= 0 {
+		da.Interval = 5
+	}
+	return &da, nil
+}
+
+// PollDeviceToken polls the host's token endpoint until the user has
+// completed the device authorization (or the device code expires), per
+// RFC 8628 section 3.4/3.5, honoring authorization_pending and slow_down
+// responses.
+func PollDeviceToken(httpClient *http.Client, svc LoginService, da *DeviceAuthorization) (*oauthToken, error) {
+	interval := time.Duration(da.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(da.ExpiresIn) * time.Second)
+
+	for {
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("device code expired before login was completed")
+		}
+		time.Sleep(interval)
+
+		resp, err := httpClient.PostForm(svc.TokenURL, url.Values{
+			"client_id":   {svc.ClientID},
+			"device_code": {da.DeviceCode},
+			"grant_type":  {deviceCodeGrantType},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to poll for device token: %w", err)
+		}
+
+		var body struct {
+			Error        string `json:"error"`
+			AccessToken  string `json:"access_token"`
+			RefreshToken string `json:"refresh_token"`
+			ExpiresIn    int    `json:"expires_in"`
+			TokenType    string `json:"token_type"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse device token response: %w", err)
+		}
+
+		switch body.Error {
+		case "":
+			return &oauthToken{
+				AccessToken:  body.AccessToken,
+				RefreshToken: body.RefreshToken,
+				ExpiresIn:    body.ExpiresIn,
+				TokenType:    body.TokenType,
+			}, nil
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += 5 * time.Second
+			continue
+		default:
+			return nil, fmt.Errorf("device login failed: %s", body.Error)
+		}
+	}
+}
+
+// DeviceLogin drives the full device authorization grant against svc for
+// the given host: it requests a device code, calls notify with a
+// human-readable message telling the user where to go and what code to
+// enter, polls until the user completes the login (or the device code
+// expires), and then persists the resulting token into host's credentials
+// via c.SetCredentials. Callers that don't already have a LoginService in
+// hand -- i.e. anything driven by a hostname rather than a test fixture --
+// should go through Config.Login instead, which discovers svc from the
+// host's login.v1 service discovery entry first.
+func (c *Config) DeviceLogin(httpClient *http.Client, host string, svc LoginService, notify func(string)) error {
+	da, err := RequestDeviceAuthorization(httpClient, svc)
+	if err != nil {
+		return err
+	}
+
+	notify(fmt.Sprintf(
+		"Open the following URL and enter the code %s to authenticate:\n    %s",
+		da.UserCode, da.VerificationURI,
+	))
+
+	tok, err := PollDeviceToken(httpClient, svc, da)
+	if err != nil {
+		return err
+	}
+
+	creds := map[string]interface{}{
+		"token":        tok.AccessToken,
+		"client_id":    svc.ClientID,
+		"token_url":    svc.TokenURL,
+		"retrieved_at": time.Now().Unix(),
+		"expires_in":   tok.ExpiresIn,
+	}
+	if tok.RefreshToken != "" {
+		creds["refresh_token"] = tok.RefreshToken
+	}
+
+	return c.SetCredentials(host, creds)
+}
+
+// oauthRefreshGraceWindow is how far ahead of a token's actual expiry
+// LoadConfig (via refreshExpiringCredentials) will proactively refresh it.
+const oauthRefreshGraceWindow = 5 * time.Minute
+
+// oauthRefreshHTTPClient is used for the refresh-token exchange that
+// LoadConfig performs on every command invocation. Unlike http.DefaultClient,
+// it carries a short timeout so that a slow or unreachable token endpoint
+// can't hang every "terraform" command behind a blocking network call.
+var oauthRefreshHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// oauthRefreshFunc exchanges a refresh token for a new access token. It is
+// a package variable, rather than called directly, so that tests can
+// substitute a fake implementation without making real network requests.
+var oauthRefreshFunc = refreshOAuthToken
+
+func refreshOAuthToken(httpClient *http.Client, tokenURL, clientID, refreshToken string) (*oauthToken, error) {
+	resp, err := httpClient.PostForm(tokenURL, url.Values{
+		"client_id":     {clientID},
+		"refresh_token": {refreshToken},
+		"grant_type":    {"refresh_token"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to refresh token: %s", resp.Status)
+	}
+
+	var tok oauthToken
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return nil, fmt.Errorf("failed to parse refresh token response: %w", err)
+	}
+	return &tok, nil
+}
+
+// refreshExpiringCredentials scans c.Credentials for entries that carry a
+// refresh_token and token_url (written there by a prior device-code login)
+// and are within oauthRefreshGraceWindow of expiry, refreshing each one in
+// place so that callers of LoadConfig see a live access token. A refresh
+// that fails is logged and leaves that entry's existing token unchanged.
+func (c *Config) refreshExpiringCredentials(httpClient *http.Client) {
+	now := time.Now()
+
+	for host, creds := range c.Credentials {
+		refreshToken, _ := creds["refresh_token"].(string)
+		tokenURL, _ := creds["token_url"].(string)
+		if refreshToken == "" || tokenURL == "" {
+			continue
+		}
+
+		expiresAt, ok := credentialExpiry(creds)
+		if !ok || expiresAt.Sub(now) > oauthRefreshGraceWindow {
+			continue
+		}
+
+		clientID, _ := creds["client_id"].(string)
+		tok, err := oauthRefreshFunc(httpClient, tokenURL, clientID, refreshToken)
+		if err != nil {
+			log.Printf("[WARN] cliconfig: failed to refresh OAuth token for host %q: %s", host, err)
+			continue
+		}
+
+		creds["token"] = tok.AccessToken
+		if tok.RefreshToken != "" {
+			creds["refresh_token"] = tok.RefreshToken
+		}
+		creds["expires_in"] = tok.ExpiresIn
+		creds["retrieved_at"] = now.Unix()
+	}
+}
+
+// credentialExpiry computes the absolute expiry time recorded in a
+// credentials entry from its retrieved_at and expires_in fields.
+func credentialExpiry(creds map[string]interface{}) (time.Time, bool) {
+	expiresIn, ok := numberField(creds["expires_in"])
+	if !ok {
+		return time.Time{}, false
+	}
+	retrievedAt, ok := numberField(creds["retrieved_at"])
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(retrievedAt), 0).Add(time.Duration(expiresIn) * time.Second), true
+}
+
+// numberField normalizes the handful of numeric types HCL/JSON decoding
+// might produce for a credentials field into a float64.
+func numberField(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		// Not expected in practice, but avoids a panic if a user hand-edits
+		// the credentials file with a quoted number.
+		var f float64
+		if _, err := fmt.Sscanf(strings.TrimSpace(n), "%g", &f); err != nil {
+			return 0, false
+		}
+		return f, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,100 @@
+package cliconfig
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// deviceFlowTestServer emulates just enough of a login.v1 host service --
+// a device-authz endpoint and a token endpoint -- to drive DeviceLogin
+// end to end. The token endpoint responds with authorization_pending for
+// the first pollsBeforeSuccess polls, then succeeds, exercising the same
+// polling loop PollDeviceToken would see against a real host.
+func deviceFlowTestServer(t *testing.T, pollsBeforeSuccess int) *httptest.Server {
+	t.Helper()
+
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device/authz", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(DeviceAuthorization{
+			DeviceCode:      "test-device-code",
+			UserCode:        "ABCD-EFGH",
+			VerificationURI: "https://example.com/device",
+			ExpiresIn:       600,
+			Interval:        1, // keep the polling loop's sleep short for tests
+		})
+	})
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		if polls < pollsBeforeSuccess {
+			polls++
+			json.NewEncoder(w).Encode(map[string]string{"error": "authorization_pending"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "test-access-token",
+			"refresh_token": "test-refresh-token",
+			"expires_in":    3600,
+			"token_type":    "bearer",
+		})
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestConfig_DeviceLogin(t *testing.T) {
+	srv := deviceFlowTestServer(t, 1)
+
+	dir := t.TempDir()
+	os.Setenv("TF_CLI_CONFIG_FILE", filepath.Join(dir, "terraformrc"))
+	defer os.Unsetenv("TF_CLI_CONFIG_FILE")
+
+	svc := LoginService{
+		ClientID:   "terraform-cli",
+		AuthzURL:   srv.URL + "/device/authz",
+		TokenURL:   srv.URL + "/oauth/token",
+		GrantTypes: []string{deviceCodeGrantType},
+	}
+
+	var notifications []string
+	c := &Config{}
+	err := c.DeviceLogin(srv.Client(), "example.com", svc, func(msg string) {
+		notifications = append(notifications, msg)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(notifications) != 1 {
+		t.Fatalf("expected one notification, got %d: %#v", len(notifications), notifications)
+	}
+	for _, want := range []string{"ABCD-EFGH", "https://example.com/device"} {
+		if !strings.Contains(notifications[0], want) {
+			t.Errorf("notification %q does not mention %q", notifications[0], want)
+		}
+	}
+
+	if got := c.Credentials["example.com"]["token"]; got != "test-access-token" {
+		t.Errorf("wrong token in memory: %#v", got)
+	}
+
+	// The token should also have been persisted to the JSON credentials
+	// file, independently of the in-memory Config, so that it's picked up
+	// by a subsequent LoadConfig.
+	fileCreds, err := loadCredentialsFile()
+	if err != nil {
+		t.Fatalf("unexpected error reading credentials file: %s", err)
+	}
+	if got := fileCreds["example.com"]["token"]; got != "test-access-token" {
+		t.Errorf("wrong token on disk: %#v", got)
+	}
+	if got := fileCreds["example.com"]["refresh_token"]; got != "test-refresh-token" {
+		t.Errorf("wrong refresh token on disk: %#v", got)
+	}
+}
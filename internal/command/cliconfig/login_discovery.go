@@ -0,0 +1,59 @@
+package cliconfig
+
+import (
+	"fmt"
+	"net/http"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+	"github.com/hashicorp/terraform-svchost/disco"
+)
+
+// discoverLoginService looks up hostname's login.v1 service discovery entry
+// and translates it into a LoginService, rather than requiring a caller to
+// build one by hand.
+func discoverLoginService(d *disco.Disco, hostname string) (LoginService, error) {
+	host, err := svchost.ForComparison(hostname)
+	if err != nil {
+		return LoginService{}, fmt.Errorf("invalid hostname %q: %w", hostname, err)
+	}
+
+	services, err := d.Discover(host)
+	if err != nil {
+		return LoginService{}, fmt.Errorf("failed to discover services for %q: %w", hostname, err)
+	}
+
+	oauthClient, err := services.ServiceOAuthClient("login.v1")
+	if err != nil {
+		return LoginService{}, fmt.Errorf("host %q does not support terraform login: %w", hostname, err)
+	}
+
+	svc := LoginService{ClientID: oauthClient.ID}
+	if oauthClient.Authz != nil {
+		svc.AuthzURL = oauthClient.Authz.String()
+	}
+	if oauthClient.Token != nil {
+		svc.TokenURL = oauthClient.Token.String()
+	}
+	for _, gt := range oauthClient.SupportedGrantTypes {
+		svc.GrantTypes = append(svc.GrantTypes, string(gt))
+	}
+	return svc, nil
+}
+
+// Login discovers hostname's login.v1 service, applies any client_id
+// override from a "login" block configured for that host, and then runs
+// the device authorization grant flow against it via DeviceLogin,
+// persisting the resulting token into the credentials file. This is the
+// single call a "terraform login" command makes; it's what ties
+// discoverLoginService and DeviceLogin together into an actual login
+// rather than two pieces of library code with no caller between them.
+func (c *Config) Login(d *disco.Disco, httpClient *http.Client, hostname string, notify func(string)) error {
+	svc, err := discoverLoginService(d, hostname)
+	if err != nil {
+		return err
+	}
+	if login, ok := c.Logins[hostname]; ok && login.ClientID != "" {
+		svc.ClientID = login.ClientID
+	}
+	return c.DeviceLogin(httpClient, hostname, svc, notify)
+}
@@ -0,0 +1,93 @@
+package cliconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+	"github.com/hashicorp/terraform-svchost/disco"
+)
+
+func testDisco(t *testing.T, hostname string, services map[string]interface{}) *disco.Disco {
+	t.Helper()
+
+	host, err := svchost.ForComparison(hostname)
+	if err != nil {
+		t.Fatalf("invalid test hostname %q: %s", hostname, err)
+	}
+
+	d := disco.New()
+	d.ForceHostServices(host, services)
+	return d
+}
+
+func TestDiscoverLoginService(t *testing.T) {
+	d := testDisco(t, "example.com", map[string]interface{}{
+		"login.v1": map[string]interface{}{
+			"client":      "terraform-cli",
+			"grant_types": []interface{}{"authz_code", deviceCodeGrantType},
+			"authz":       "https://example.com/authz",
+			"token":       "https://example.com/token",
+		},
+	})
+
+	svc, err := discoverLoginService(d, "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if svc.ClientID != "terraform-cli" {
+		t.Errorf("wrong client id %q", svc.ClientID)
+	}
+	if svc.AuthzURL != "https://example.com/authz" {
+		t.Errorf("wrong authz URL %q", svc.AuthzURL)
+	}
+	if svc.TokenURL != "https://example.com/token" {
+		t.Errorf("wrong token URL %q", svc.TokenURL)
+	}
+	if !svc.SupportsDeviceCode() {
+		t.Errorf("expected discovered service to support device code, got %#v", svc.GrantTypes)
+	}
+}
+
+func TestConfig_Login(t *testing.T) {
+	srv := deviceFlowTestServer(t, 0)
+
+	dir := t.TempDir()
+	os.Setenv("TF_CLI_CONFIG_FILE", filepath.Join(dir, "terraformrc"))
+	defer os.Unsetenv("TF_CLI_CONFIG_FILE")
+
+	d := testDisco(t, "example.com", map[string]interface{}{
+		"login.v1": map[string]interface{}{
+			"client":      "terraform-cli",
+			"grant_types": []interface{}{deviceCodeGrantType},
+			"authz":       srv.URL + "/device/authz",
+			"token":       srv.URL + "/oauth/token",
+		},
+	})
+
+	c := &Config{
+		Logins: map[string]*ConfigLogin{
+			"example.com": {ClientID: "overridden-client-id"},
+		},
+	}
+
+	var notified bool
+	err := c.Login(d, srv.Client(), "example.com", func(string) {
+		notified = true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !notified {
+		t.Error("expected a notification with the verification URL and user code")
+	}
+
+	if got := c.Credentials["example.com"]["token"]; got != "test-access-token" {
+		t.Errorf("wrong token %#v", got)
+	}
+	if got := c.Credentials["example.com"]["client_id"]; got != "overridden-client-id" {
+		t.Errorf("expected the login block's client_id override to be used, got %#v", got)
+	}
+}
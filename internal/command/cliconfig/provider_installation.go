@@ -0,0 +1,113 @@
+package cliconfig
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+)
+
+// ProviderInstallation represents a single provider_installation block in
+// the CLI configuration, which customizes how Terraform locates and
+// installs provider plugins.
+type ProviderInstallation struct {
+	Methods []*ProviderInstallationMethod
+}
+
+// ProviderInstallationMethod represents a single block nested inside a
+// provider_installation block, specifying one strategy Terraform should
+// try when installing providers, along with which providers it applies to.
+type ProviderInstallationMethod struct {
+	Location ProviderInstallationLocation
+	Include  []string
+	Exclude  []string
+}
+
+// ProviderInstallationLocation is an interface implemented by the location
+// types below, representing where a ProviderInstallationMethod will look
+// for provider plugins.
+type ProviderInstallationLocation interface {
+	providerInstallationLocation()
+}
+
+// ProviderInstallationFilesystemMirror is a ProviderInstallationLocation
+// that reads providers from a filesystem mirror directory.
+type ProviderInstallationFilesystemMirror string
+
+func (l ProviderInstallationFilesystemMirror) providerInstallationLocation() {}
+
+// ProviderInstallationNetworkMirror is a ProviderInstallationLocation that
+// reads providers from an HTTP network mirror.
+type ProviderInstallationNetworkMirror string
+
+func (l ProviderInstallationNetworkMirror) providerInstallationLocation() {}
+
+// ProviderInstallationDirect is a ProviderInstallationLocation representing
+// Terraform's default behavior of installing directly from a provider's
+// origin registry.
+type ProviderInstallationDirect struct{}
+
+func (l ProviderInstallationDirect) providerInstallationLocation() {}
+
+func parseProviderInstallationFromObject(obj *ast.ObjectItem) ([]*ProviderInstallation, error) {
+	var result []*ProviderInstallation
+
+	objType, ok := obj.Val.(*ast.ObjectType)
+	if !ok {
+		return nil, fmt.Errorf("provider_installation block must be a block, not an attribute")
+	}
+
+	installation := &ProviderInstallation{}
+	for _, item := range objType.List.Items {
+		if len(item.Keys) == 0 {
+			continue
+		}
+		blockType := item.Keys[0].Token.Value().(string)
+
+		methodObjType, ok := item.Val.(*ast.ObjectType)
+		if !ok {
+			return nil, fmt.Errorf("provider_installation.%s must be a block", blockType)
+		}
+
+		var raw struct {
+			Include []string `hcl:"include"`
+			Exclude []string `hcl:"exclude"`
+		}
+		if err := hcl.DecodeObject(&raw, methodObjType); err != nil {
+			return nil, fmt.Errorf("invalid provider_installation.%s block: %s", blockType, err)
+		}
+
+		var location ProviderInstallationLocation
+		switch blockType {
+		case "direct":
+			location = ProviderInstallationDirect{}
+		case "filesystem_mirror":
+			var mirror struct {
+				Path string `hcl:"path"`
+			}
+			if err := hcl.DecodeObject(&mirror, methodObjType); err != nil {
+				return nil, fmt.Errorf("invalid provider_installation.filesystem_mirror block: %s", err)
+			}
+			location = ProviderInstallationFilesystemMirror(mirror.Path)
+		case "network_mirror":
+			var mirror struct {
+				URL string `hcl:"url"`
+			}
+			if err := hcl.DecodeObject(&mirror, methodObjType); err != nil {
+				return nil, fmt.Errorf("invalid provider_installation.network_mirror block: %s", err)
+			}
+			location = ProviderInstallationNetworkMirror(mirror.URL)
+		default:
+			return nil, fmt.Errorf("unknown provider_installation method type %q", blockType)
+		}
+
+		installation.Methods = append(installation.Methods, &ProviderInstallationMethod{
+			Location: location,
+			Include:  raw.Include,
+			Exclude:  raw.Exclude,
+		})
+	}
+
+	result = append(result, installation)
+	return result, nil
+}
@@ -0,0 +1,36 @@
+// Package command implements Terraform's top-level CLI commands.
+package command
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-svchost/disco"
+	"github.com/hashicorp/terraform/internal/command/cliconfig"
+)
+
+// Login runs the OAuth 2.0 Device Authorization Grant flow against
+// hostname's login.v1 service (as discovered via hostname's service
+// discovery document) and saves the resulting token into the CLI
+// credentials file, printing the device code and verification URL to out
+// as it goes. This is the function the "terraform login" command wires
+// up to its CLI scaffolding.
+func Login(hostname string, out io.Writer) error {
+	config, diags := cliconfig.LoadConfig()
+	if diags.HasErrors() {
+		return diags.Err()
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	err := config.Login(disco.New(), httpClient, hostname, func(msg string) {
+		fmt.Fprintln(out, msg)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to log in to %q: %w", hostname, err)
+	}
+
+	fmt.Fprintf(out, "Successfully logged in to %q.\n", hostname)
+	return nil
+}
@@ -0,0 +1,116 @@
+package configschema
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ImpliedType returns the cty.Type that would result from decoding a
+// configuration block using the given block schema.
+//
+// ImpliedType always returns a result, even if the given schema is
+// inconsistent. Code that creates configschema.Block objects should be
+// tested using the InternalValidate method to detect any inconsistencies
+// that would cause this method to fall back on defaults or omit parts of
+// the type.
+func (b *Block) ImpliedType() cty.Type {
+	if b == nil {
+		return cty.EmptyObject
+	}
+
+	atys := make(map[string]cty.Type)
+
+	for name, attrS := range b.Attributes {
+		atys[name] = attrS.ImpliedType()
+	}
+
+	for name, blockS := range b.BlockTypes {
+		atys[name] = blockS.impliedType()
+	}
+
+	return cty.Object(atys)
+}
+
+// ImpliedType returns the cty.Type that would result from decoding a
+// configuration value using the given attribute schema.
+func (a *Attribute) ImpliedType() cty.Type {
+	if a.NestedType != nil {
+		return a.NestedType.ImpliedType()
+	}
+	return a.Type
+}
+
+func (b *NestedBlock) impliedType() cty.Type {
+	coll := b.Block.ImpliedType()
+
+	switch b.Nesting {
+	case NestingSingle, NestingGroup:
+		return coll
+	case NestingList:
+		return cty.List(coll)
+	case NestingSet:
+		return cty.Set(coll)
+	case NestingMap:
+		return cty.Map(coll)
+	default:
+		// Invalid nesting type is ignored; this should be checked via
+		// InternalValidate.
+		return cty.EmptyObject
+	}
+}
+
+// ImpliedType returns the cty.Type that would result from decoding a
+// configuration value using the given object schema.
+//
+// Unlike Block.ImpliedType, this makes use of cty's support for optional
+// object attributes, marking any attribute that sets Optional or Computed
+// as optional in the resulting type (an attribute that sets none of
+// Required, Optional or Computed stays required), since
+// Object is generally used to describe more lenient "nested attribute"
+// shapes than legacy nested blocks.
+func (o *Object) ImpliedType() cty.Type {
+	if o == nil {
+		return cty.EmptyObject
+	}
+
+	atys := make(map[string]cty.Type)
+	var optional []string
+
+	for name, attrS := range o.Attributes {
+		atys[name] = attrS.ImpliedType()
+		if attrS.Optional || attrS.Computed {
+			optional = append(optional, name)
+		}
+	}
+
+	obj := cty.ObjectWithOptionalAttrs(atys, optional)
+
+	switch o.Nesting {
+	case NestingList:
+		return cty.List(obj)
+	case NestingSet:
+		return cty.Set(obj)
+	case NestingMap:
+		return cty.Map(obj)
+	default:
+		return obj
+	}
+}
+
+// ContainsSensitive returns true if any of the attributes of the receiving
+// block, or any of its nested attributes and blocks, are marked as
+// sensitive.
+func (o *Object) ContainsSensitive() bool {
+	if o == nil {
+		return false
+	}
+
+	for _, attrS := range o.Attributes {
+		if attrS.Sensitive {
+			return true
+		}
+		if attrS.NestedType != nil && attrS.NestedType.ContainsSensitive() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+package configschema
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// jsonSchemaDialect is the JSON Schema dialect that JSONSchema documents
+// identify themselves as conforming to.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema renders the receiving block's shape as a Draft 2020-12 JSON
+// Schema document, suitable for consumption by editors, linters, and
+// documentation generators that understand JSON Schema but not
+// Terraform's own schema model.
+//
+// The returned schema describes the same shape that ImpliedType would
+// decode a configuration value into, except expressed in terms of a
+// JSON document rather than an HCL configuration block.
+func (b *Block) JSONSchema() ([]byte, error) {
+	schema := b.jsonSchemaNode()
+	schema["$schema"] = jsonSchemaDialect
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func (b *Block) jsonSchemaNode() map[string]interface{} {
+	if b == nil {
+		return map[string]interface{}{"type": "object"}
+	}
+
+	props := map[string]interface{}{}
+	var required []string
+	for name, attrS := range b.Attributes {
+		props[name] = attrS.jsonSchemaNode()
+		// Unlike Object.jsonSchemaNode, this can trust Required directly:
+		// Block.ImpliedType builds a plain cty.Object rather than using
+		// cty.ObjectWithOptionalAttrs, so there's no Optional-or-Computed
+		// convention being silently relied on here for the decoded type to
+		// agree with -- a block attribute is only ever required in the
+		// JSON Schema sense if Required itself is set.
+		if attrS.Required {
+			required = append(required, name)
+		}
+	}
+	for name, blockS := range b.BlockTypes {
+		props[name] = blockS.jsonSchemaNode()
+	}
+
+	node := map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		node["required"] = required
+	}
+	annotateDescription(node, b.Description, b.DescriptionKind)
+	if b.Deprecated {
+		node["deprecated"] = true
+	}
+	return node
+}
+
+// JSONSchema renders the receiving object's shape as a Draft 2020-12 JSON
+// Schema document, in the same manner as Block.JSONSchema.
+func (o *Object) JSONSchema() ([]byte, error) {
+	schema := o.jsonSchemaNode()
+	schema["$schema"] = jsonSchemaDialect
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func (o *Object) jsonSchemaNode() map[string]interface{} {
+	if o == nil {
+		return map[string]interface{}{"type": "object"}
+	}
+
+	props := map[string]interface{}{}
+	var required []string
+	for name, attrS := range o.Attributes {
+		props[name] = attrS.jsonSchemaNode()
+		// ImpliedType (via cty.ObjectWithOptionalAttrs) treats an attribute
+		// as optional only if Optional or Computed is set, so an attribute
+		// with neither flag is implicitly required even if Required itself
+		// wasn't set. Mirror that rule here rather than trusting Required
+		// alone.
+		if !attrS.Optional && !attrS.Computed {
+			required = append(required, name)
+		}
+	}
+
+	node := map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		node["required"] = required
+	}
+
+	return wrapNesting(node, o.Nesting, 0, 0)
+}
+
+func (a *Attribute) jsonSchemaNode() map[string]interface{} {
+	var node map[string]interface{}
+	if a.NestedType != nil {
+		node = a.NestedType.jsonSchemaNode()
+	} else {
+		node = ctyTypeJSONSchema(a.Type)
+	}
+
+	annotateDescription(node, a.Description, a.DescriptionKind)
+	if a.Sensitive {
+		node["writeOnly"] = true
+	}
+	if a.Deprecated {
+		node["deprecated"] = true
+	}
+	return node
+}
+
+func (b *NestedBlock) jsonSchemaNode() map[string]interface{} {
+	inner := b.Block.jsonSchemaNode()
+	return wrapNesting(inner, b.Nesting, b.MinItems, b.MaxItems)
+}
+
+// wrapNesting applies the collection shape implied by a NestingMode around
+// an already-built object schema node, mirroring how ImpliedType wraps an
+// object type in cty.List, cty.Set, or cty.Map.
+func wrapNesting(node map[string]interface{}, nesting NestingMode, minItems, maxItems int) map[string]interface{} {
+	switch nesting {
+	case NestingList, NestingSet:
+		wrapped := map[string]interface{}{
+			"type":  "array",
+			"items": node,
+		}
+		if minItems > 0 {
+			wrapped["minItems"] = minItems
+		}
+		if maxItems > 0 {
+			wrapped["maxItems"] = maxItems
+		}
+		if nesting == NestingSet {
+			wrapped["uniqueItems"] = true
+		}
+		return wrapped
+	case NestingMap:
+		return map[string]interface{}{
+			"type": "object",
+			"patternProperties": map[string]interface{}{
+				".*": node,
+			},
+		}
+	default: // NestingSingle, NestingGroup, or unset
+		return node
+	}
+}
+
+// ctyTypeJSONSchema converts a cty.Type, as used for the Type of an
+// Attribute that doesn't use NestedType, into an equivalent JSON Schema
+// fragment.
+func ctyTypeJSONSchema(ty cty.Type) map[string]interface{} {
+	switch {
+	case ty == cty.String:
+		return map[string]interface{}{"type": "string"}
+	case ty == cty.Number:
+		return map[string]interface{}{"type": "number"}
+	case ty == cty.Bool:
+		return map[string]interface{}{"type": "boolean"}
+
+	case ty == cty.DynamicPseudoType:
+		return map[string]interface{}{
+			"oneOf": []interface{}{
+				map[string]interface{}{"type": "string"},
+				map[string]interface{}{"type": "number"},
+				map[string]interface{}{"type": "boolean"},
+				map[string]interface{}{"type": "array"},
+				map[string]interface{}{"type": "object"},
+				map[string]interface{}{"type": "null"},
+			},
+		}
+
+	case ty.IsListType() || ty.IsSetType():
+		node := map[string]interface{}{
+			"type":  "array",
+			"items": ctyTypeJSONSchema(ty.ElementType()),
+		}
+		if ty.IsSetType() {
+			node["uniqueItems"] = true
+		}
+		return node
+
+	case ty.IsTupleType():
+		etys := ty.TupleElementTypes()
+		items := make([]interface{}, len(etys))
+		for i, ety := range etys {
+			items[i] = ctyTypeJSONSchema(ety)
+		}
+		return map[string]interface{}{
+			"type":        "array",
+			"prefixItems": items,
+			"items":       false,
+		}
+
+	case ty.IsMapType():
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": ctyTypeJSONSchema(ty.ElementType()),
+		}
+
+	case ty.IsObjectType():
+		props := map[string]interface{}{}
+		var required []string
+		for name, aty := range ty.AttributeTypes() {
+			props[name] = ctyTypeJSONSchema(aty)
+			if !ty.AttributeOptional(name) {
+				required = append(required, name)
+			}
+		}
+		node := map[string]interface{}{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+		if len(required) > 0 {
+			sort.Strings(required)
+			node["required"] = required
+		}
+		return node
+
+	default:
+		// Anything else (e.g. capsule types) has no meaningful JSON
+		// Schema representation, so we fall back to allowing any value.
+		return map[string]interface{}{}
+	}
+}
+
+func annotateDescription(node map[string]interface{}, description string, kind StringKind) {
+	if description == "" {
+		return
+	}
+	node["description"] = description
+	node["x-terraform-description-kind"] = kind.String()
+}
@@ -0,0 +1,310 @@
+package configschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TestBlockJSONSchema verifies both that JSONSchema produces valid JSON and
+// that the resulting document's shape is consistent with what ImpliedType
+// computes for the same Block, by checking that representative
+// HCL-decoded JSON configuration values would satisfy the generated
+// schema's structural constraints (required properties, nested types).
+func TestBlockJSONSchema(t *testing.T) {
+	schema := &Block{
+		Attributes: map[string]*Attribute{
+			"name": {
+				Type:        cty.String,
+				Required:    true,
+				Description: "The name of the thing.",
+			},
+			"tags": {
+				Type:     cty.Map(cty.String),
+				Optional: true,
+			},
+			"password": {
+				Type:      cty.String,
+				Optional:  true,
+				Sensitive: true,
+			},
+			// "internal_id" sets none of Required/Optional/Computed. Unlike
+			// the equivalent case for Object (see TestObjectJSONSchema),
+			// this is NOT implicitly required: Block.ImpliedType builds a
+			// plain cty.Object with no optional-attribute tracking, so
+			// Required is the only signal jsonSchemaNode has for whether an
+			// attribute belongs in "required".
+			"internal_id": {
+				Type: cty.String,
+			},
+		},
+		BlockTypes: map[string]*NestedBlock{
+			"rule": {
+				Nesting: NestingList,
+				Block: Block{
+					Attributes: map[string]*Attribute{
+						"port": {
+							Type:     cty.Number,
+							Required: true,
+						},
+					},
+				},
+				MinItems: 0,
+				MaxItems: 0,
+			},
+		},
+	}
+
+	raw, err := schema.JSONSchema()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("generated schema is not valid JSON: %s", err)
+	}
+
+	if doc["type"] != "object" {
+		t.Errorf("wrong top-level type %#v", doc["type"])
+	}
+
+	props, ok := doc["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("missing properties object")
+	}
+
+	// A representative decoded configuration value for this block should
+	// satisfy the properties declared in the schema: "name" is required
+	// and a string, "tags" is an optional map of strings, "password" is
+	// marked writeOnly, and "rule" is an array of objects with a required
+	// numeric "port".
+	example := map[string]interface{}{
+		"name": "example",
+		"tags": map[string]interface{}{"env": "prod"},
+		"rule": []interface{}{
+			map[string]interface{}{"port": 443.0},
+		},
+	}
+	if err := validateAgainstObjectSchema(example, doc); err != nil {
+		t.Errorf("representative document does not satisfy generated schema: %s", err)
+	}
+
+	nameProp, ok := props["name"].(map[string]interface{})
+	if !ok || nameProp["type"] != "string" {
+		t.Errorf("wrong schema for \"name\": %#v", props["name"])
+	}
+	if nameProp["description"] != "The name of the thing." {
+		t.Errorf("missing description for \"name\": %#v", nameProp)
+	}
+
+	passwordProp, ok := props["password"].(map[string]interface{})
+	if !ok || passwordProp["writeOnly"] != true {
+		t.Errorf("expected \"password\" to be writeOnly: %#v", props["password"])
+	}
+
+	ruleProp, ok := props["rule"].(map[string]interface{})
+	if !ok || ruleProp["type"] != "array" {
+		t.Errorf("wrong schema for \"rule\": %#v", props["rule"])
+	}
+
+	required, _ := doc["required"].([]interface{})
+	if len(required) != 1 || required[0] != "name" {
+		t.Errorf("wrong required list %#v", doc["required"])
+	}
+}
+
+// TestObjectJSONSchema is the Object-typed counterpart to
+// TestBlockJSONSchema, covering the NestedType/optional-attribute path.
+func TestObjectJSONSchema(t *testing.T) {
+	schema := &Object{
+		Nesting: NestingSingle,
+		Attributes: map[string]*Attribute{
+			"id": {
+				Type:     cty.String,
+				Required: true,
+			},
+			"enabled": {
+				Type:     cty.Bool,
+				Optional: true,
+			},
+			// "name" sets none of Required/Optional/Computed. ImpliedType
+			// (via cty.ObjectWithOptionalAttrs) still treats this as
+			// required, since only Optional || Computed marks an attribute
+			// optional in the decoded type -- the generated schema's
+			// "required" list needs to agree with that.
+			"name": {
+				Type: cty.String,
+			},
+		},
+	}
+
+	raw, err := schema.JSONSchema()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("generated schema is not valid JSON: %s", err)
+	}
+
+	example := map[string]interface{}{
+		"id":   "abc123",
+		"name": "example",
+	}
+	if err := validateAgainstObjectSchema(example, doc); err != nil {
+		t.Errorf("representative document does not satisfy generated schema: %s", err)
+	}
+
+	missingRequired := map[string]interface{}{
+		"enabled": true,
+		"name":    "example",
+	}
+	if err := validateAgainstObjectSchema(missingRequired, doc); err == nil {
+		t.Errorf("expected a document missing the required \"id\" property to fail validation")
+	}
+
+	missingUnflaggedRequired := map[string]interface{}{
+		"id": "abc123",
+	}
+	if err := validateAgainstObjectSchema(missingUnflaggedRequired, doc); err == nil {
+		t.Errorf("expected a document missing the required \"name\" property to fail validation")
+	}
+}
+
+// validateAgainstObjectSchema is a minimal structural validator covering the
+// handful of JSON Schema keywords this package emits (type, required,
+// properties, additionalProperties, patternProperties, items, prefixItems,
+// oneOf) -- enough to confirm that the documents ImpliedType would decode
+// really do satisfy the schema JSONSchema produces for the same
+// Block/Object, without pulling in a full external JSON Schema
+// implementation. It recurses into nested object and array values, so it
+// checks nested values against items, prefixItems, additionalProperties and
+// patternProperties schemas, not just the top-level object. Numbers must be
+// float64, as encoding/json decodes them.
+func validateAgainstObjectSchema(doc map[string]interface{}, schema map[string]interface{}) error {
+	return validateAgainstSchema(doc, schema)
+}
+
+func validateAgainstSchema(doc interface{}, schema map[string]interface{}) error {
+	if alts, ok := schema["oneOf"].([]interface{}); ok {
+		for _, rawAlt := range alts {
+			if alt, ok := rawAlt.(map[string]interface{}); ok {
+				if validateAgainstSchema(doc, alt) == nil {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("value %#v does not satisfy any of the oneOf alternatives", doc)
+	}
+
+	switch schema["type"] {
+	case "string":
+		if _, ok := doc.(string); !ok {
+			return fmt.Errorf("expected a string, got %#v", doc)
+		}
+	case "number":
+		if _, ok := doc.(float64); !ok {
+			return fmt.Errorf("expected a number, got %#v", doc)
+		}
+	case "boolean":
+		if _, ok := doc.(bool); !ok {
+			return fmt.Errorf("expected a boolean, got %#v", doc)
+		}
+	case "array":
+		elems, ok := doc.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected an array, got %#v", doc)
+		}
+		if prefixItems, ok := schema["prefixItems"].([]interface{}); ok {
+			if len(elems) != len(prefixItems) {
+				return fmt.Errorf("expected a tuple of length %d, got %d elements", len(prefixItems), len(elems))
+			}
+			for i, rawItemSchema := range prefixItems {
+				itemSchema, ok := rawItemSchema.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if err := validateAgainstSchema(elems[i], itemSchema); err != nil {
+					return fmt.Errorf("element %d: %w", i, err)
+				}
+			}
+			break
+		}
+		if itemSchema, ok := schema["items"].(map[string]interface{}); ok {
+			for i, elem := range elems {
+				if err := validateAgainstSchema(elem, itemSchema); err != nil {
+					return fmt.Errorf("element %d: %w", i, err)
+				}
+			}
+		}
+	case "object":
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected an object, got %#v", doc)
+		}
+
+		for _, rawName := range mustStringSlice(schema["required"]) {
+			if _, ok := obj[rawName]; !ok {
+				return fmt.Errorf("missing required property %q", rawName)
+			}
+		}
+
+		props, _ := schema["properties"].(map[string]interface{})
+		patternProps, _ := schema["patternProperties"].(map[string]interface{})
+
+		for name, value := range obj {
+			if propSchema, ok := props[name].(map[string]interface{}); ok {
+				if err := validateAgainstSchema(value, propSchema); err != nil {
+					return fmt.Errorf("property %q: %w", name, err)
+				}
+				continue
+			}
+
+			matchedPattern := false
+			for pattern, rawPatternSchema := range patternProps {
+				if !regexp.MustCompile(pattern).MatchString(name) {
+					continue
+				}
+				matchedPattern = true
+				if patternSchema, ok := rawPatternSchema.(map[string]interface{}); ok {
+					if err := validateAgainstSchema(value, patternSchema); err != nil {
+						return fmt.Errorf("property %q: %w", name, err)
+					}
+				}
+			}
+			if matchedPattern {
+				continue
+			}
+
+			if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+				return fmt.Errorf("unexpected property %q", name)
+			}
+			if additionalSchema, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				if err := validateAgainstSchema(value, additionalSchema); err != nil {
+					return fmt.Errorf("property %q: %w", name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func mustStringSlice(v interface{}) []string {
+	raw, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(raw))
+	for _, item := range raw {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
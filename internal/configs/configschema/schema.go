@@ -0,0 +1,167 @@
+// Package configschema contains types for describing the expected shape of
+// configuration blocks, independently of the HCL or JSON syntax used to
+// write them, that can also be used to drive decoding and validation of
+// configuration against a schema.
+package configschema
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Block represents a configuration block.
+//
+// "Block" here is a logical grouping construct, though it happens to map
+// directly onto the physical block syntax of HCL. It could also be thought
+// of as like a "message type" in a format like protobuf, defining a
+// structural type of a (possibly-repeated) nested value.
+type Block struct {
+	// Attributes describes any attributes that may appear directly inside
+	// the block.
+	Attributes map[string]*Attribute
+
+	// BlockTypes describes any nested block types that may appear directly
+	// inside the block.
+	BlockTypes map[string]*NestedBlock
+
+	// Description, if set, is the description for the block as a whole,
+	// in the content given by DescriptionKind.
+	Description     string
+	DescriptionKind StringKind
+
+	// Deprecated, if set, indicates that a decoder should emit a warning
+	// when this attribute is used.
+	Deprecated bool
+}
+
+// Attribute represents a configuration attribute, within a block.
+type Attribute struct {
+	// Type is the type constraint that a value assigned to this attribute
+	// must satisfy. If NestedType is set, this must not be set, and
+	// vice-versa.
+	Type cty.Type
+
+	// NestedType, if set, indicates that this attribute's value is itself
+	// made up of nested attributes. This is a more recent alternative to
+	// using NestingSingle/NestingList/etc blocks for this purpose.
+	NestedType *Object
+
+	// Description, if set, gives a human-readable description of the
+	// purpose and usage of the attribute, in the content given by
+	// DescriptionKind.
+	Description     string
+	DescriptionKind StringKind
+
+	// Required, Optional, and Computed describe the meaning of this
+	// attribute, in terms mirroring the schema model used by
+	// terraform-plugin-sdk: exactly one of these should be set to true,
+	// except for the combination of Optional and Computed both set which
+	// has its own special meaning.
+	Required bool
+	Optional bool
+	Computed bool
+
+	// Sensitive, if set, indicates that this attribute's value should be
+	// considered sensitive and subject to masking in the UI.
+	Sensitive bool
+
+	// Deprecated, if set, indicates that a decoder should emit a warning
+	// when this attribute is used.
+	Deprecated bool
+}
+
+// NestedBlock represents the embedding of one block within another.
+type NestedBlock struct {
+	// Block is the description of the block that's nested.
+	Block
+
+	// Nesting provides the nesting mode for the child block, which
+	// determines how many instances of the block are allowed, how many
+	// labels it expects, and how the resulting data will be converted into
+	// a data structure.
+	Nesting NestingMode
+
+	// MinItems and MaxItems set, for the NestingList and NestingSet nesting
+	// modes, lower and upper limits on the number of child blocks allowed
+	// of the given type. If both are left at zero, no limit is applied.
+	//
+	// As a special case, both values can be set to 1 to indicate that
+	// an attribute is required.
+	//
+	// MinItems and MaxItems are not used for other nesting modes, and
+	// must both be left at zero.
+	MinItems, MaxItems int
+}
+
+// NestingMode is an enum used with the Object and NestedBlock types to
+// describe how the nested object or block is nested within its parent.
+type NestingMode int
+
+const (
+	nestingModeInvalid NestingMode = iota
+
+	// NestingSingle indicates that only a single instance of a given
+	// block type is allowed, with no labels, and its content should be
+	// provided directly as an object value.
+	NestingSingle
+
+	// NestingGroup is similar to NestingSingle in that it calls for only a
+	// single instance of a given block type with no labels, but it
+	// additionally guarantees that its result will never be null, even if
+	// the block is absent, and instead the nested attributes and blocks
+	// will be treated as absent in that case.
+	NestingGroup
+
+	// NestingList indicates that multiple blocks of the given type are
+	// allowed, with no labels, and their corresponding objects should be
+	// provided in a list.
+	NestingList
+
+	// NestingSet indicates that multiple blocks of the given type are
+	// allowed, with no labels, and their corresponding objects should be
+	// provided in a set.
+	NestingSet
+
+	// NestingMap indicates that multiple blocks of the given type are
+	// allowed, each with a single label, and their corresponding objects
+	// should be provided in a map whose keys are the labels.
+	NestingMap
+)
+
+// Object represents the embedding of a nested set of attributes within an
+// attribute, as an alternative to the legacy pattern of using a nested
+// block type for this purpose.
+type Object struct {
+	// Attributes describes the attributes that belong to the object.
+	Attributes map[string]*Attribute
+
+	// Nesting provides the nesting mode for the object, which has similar
+	// meaning to the nesting mode for a NestedBlock, but a different set
+	// of values are supported here.
+	Nesting NestingMode
+}
+
+// StringKind describes the format of a string, such as a Description,
+// used to determine how the string should be parsed or rendered.
+type StringKind int
+
+const (
+	// StringPlain indicates a string that should be used as-is, with no
+	// special parsing or escaping assumed.
+	StringPlain StringKind = iota
+
+	// StringMarkdown indicates a string that contains Markdown markup
+	// that should be rendered before display to an end-user.
+	StringMarkdown
+)
+
+// String returns the machine-readable name used to identify this kind of
+// string in serialized formats such as the JSON Schema produced by
+// Block.JSONSchema.
+func (k StringKind) String() string {
+	switch k {
+	case StringMarkdown:
+		return "markdown"
+	default:
+		return "plain"
+	}
+}